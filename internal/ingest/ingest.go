@@ -0,0 +1,68 @@
+// Package ingest adapts foreign CI test-result formats (JUnit XML, TAP) into
+// the plain []TestResult shape the Gap Score pipeline already understands, so
+// buildReport and everything downstream of it never need to know where a
+// result came from.
+package ingest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TestResult is the ingest package's view of a single test outcome. Callers
+// are expected to map this onto their own equivalent struct.
+type TestResult struct {
+	Name     string
+	Status   string
+	Category string
+	Message  string
+}
+
+// CategoryRule maps tests whose classname or name matches Pattern to Category.
+type CategoryRule struct {
+	Category string
+	Pattern  *regexp.Regexp
+}
+
+// CategoryMap is an ordered set of CategoryRules; the first matching rule wins.
+type CategoryMap []CategoryRule
+
+// ParseCategoryMap parses specs of the form "category:regex", as supplied via
+// repeated --category-map flags.
+func ParseCategoryMap(specs []string) (CategoryMap, error) {
+	var rules CategoryMap
+	for _, spec := range specs {
+		idx := strings.Index(spec, ":")
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid --category-map %q: expected category:regex", spec)
+		}
+		category, pattern := spec[:idx], spec[idx+1:]
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --category-map %q: %w", spec, err)
+		}
+		rules = append(rules, CategoryRule{Category: category, Pattern: re})
+	}
+	return rules, nil
+}
+
+// Categorize returns the category of the first rule matching s, or "" if no
+// rule matches.
+func (m CategoryMap) Categorize(s string) string {
+	for _, rule := range m {
+		if rule.Pattern.MatchString(s) {
+			return rule.Category
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package ingest
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+}
+
+type junitSuite struct {
+	Cases  []junitCase  `xml:"testcase"`
+	Suites []junitSuite `xml:"testsuite"`
+}
+
+// ParseJUnit parses a JUnit XML report, accepting both a <testsuites> root
+// wrapping one or more <testsuite> elements and a single bare <testsuite>
+// root, with classname optionally fed through categoryMap to infer a
+// category (JUnit has no native category field).
+func ParseJUnit(data []byte, categoryMap CategoryMap) ([]TestResult, error) {
+	var wrapper struct {
+		Suites []junitSuite `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(data, &wrapper); err != nil {
+		return nil, fmt.Errorf("parsing JUnit XML: %w", err)
+	}
+
+	suites := wrapper.Suites
+	if len(suites) == 0 {
+		var single junitSuite
+		if err := xml.Unmarshal(data, &single); err != nil {
+			return nil, fmt.Errorf("parsing JUnit XML: %w", err)
+		}
+		suites = []junitSuite{single}
+	}
+
+	var results []TestResult
+	var walk func(s junitSuite)
+	walk = func(s junitSuite) {
+		for _, c := range s.Cases {
+			results = append(results, junitCaseToResult(c, categoryMap))
+		}
+		for _, nested := range s.Suites {
+			walk(nested)
+		}
+	}
+	for _, s := range suites {
+		walk(s)
+	}
+	return results, nil
+}
+
+func junitCaseToResult(c junitCase, categoryMap CategoryMap) TestResult {
+	status := "passed"
+	message := ""
+	switch {
+	case c.Failure != nil:
+		status = "failed"
+		message = firstNonEmpty(c.Failure.Message, c.Failure.Text)
+	case c.Error != nil:
+		status = "error"
+		message = firstNonEmpty(c.Error.Message, c.Error.Text)
+	case c.Skipped != nil:
+		status = "skipped"
+		message = firstNonEmpty(c.Skipped.Message, c.Skipped.Text)
+	}
+
+	category := categoryMap.Categorize(c.ClassName)
+	if category == "" {
+		category = categoryMap.Categorize(c.Name)
+	}
+
+	return TestResult{
+		Name:     c.Name,
+		Status:   status,
+		Category: category,
+		Message:  message,
+	}
+}
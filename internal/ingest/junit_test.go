@@ -0,0 +1,61 @@
+package ingest
+
+import "testing"
+
+const junitFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="checkout">
+    <testcase name="rejects negative amount" classname="checkout.EdgeCaseTest"/>
+    <testcase name="rejects sql injection in search" classname="checkout.SecurityTest">
+      <failure message="input was not sanitized">expected 400, got 200</failure>
+    </testcase>
+    <testcase name="flaky network call" classname="checkout.IntegrationTest">
+      <error message="connection reset">dial tcp: connection reset by peer</error>
+    </testcase>
+    <testcase name="not yet implemented" classname="checkout.TodoTest">
+      <skipped message="not implemented"/>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+func TestParseJUnit(t *testing.T) {
+	categoryMap, err := ParseCategoryMap([]string{"security:(?i)security"})
+	if err != nil {
+		t.Fatalf("ParseCategoryMap: %v", err)
+	}
+
+	results, err := ParseJUnit([]byte(junitFixture), categoryMap)
+	if err != nil {
+		t.Fatalf("ParseJUnit: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+
+	want := []TestResult{
+		{Name: "rejects negative amount", Status: "passed", Category: ""},
+		{Name: "rejects sql injection in search", Status: "failed", Category: "security", Message: "input was not sanitized"},
+		{Name: "flaky network call", Status: "error", Message: "connection reset"},
+		{Name: "not yet implemented", Status: "skipped", Message: "not implemented"},
+	}
+	for i, w := range want {
+		got := results[i]
+		if got.Name != w.Name || got.Status != w.Status || got.Category != w.Category || got.Message != w.Message {
+			t.Errorf("result[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestParseJUnitBareTestsuiteRoot(t *testing.T) {
+	const fixture = `<testsuite name="checkout">
+  <testcase name="happy path checkout" classname="checkout.HappyPathTest"/>
+</testsuite>`
+
+	results, err := ParseJUnit([]byte(fixture), nil)
+	if err != nil {
+		t.Fatalf("ParseJUnit: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "happy path checkout" || results[0].Status != "passed" {
+		t.Fatalf("got %+v, want one passed test", results)
+	}
+}
@@ -0,0 +1,81 @@
+package ingest
+
+import (
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	tapLineRe    = regexp.MustCompile(`^(ok|not ok)\s+\d*\s*-?\s*(.*)$`)
+	tapDirective = regexp.MustCompile(`^(.*?)\s*#\s*(SKIP|TODO)\b.*$`)
+)
+
+// ParseTAP parses a TAP 13 stream: "ok"/"not ok" lines, optional SKIP/TODO
+// directives, and an optional YAML diagnostic block (delimited by "---" and
+// "...") following a line, whose "message" field is used as the failure
+// message.
+func ParseTAP(data []byte, categoryMap CategoryMap) ([]TestResult, error) {
+	lines := strings.Split(string(data), "\n")
+
+	var results []TestResult
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(strings.TrimRight(lines[i], "\r"))
+		m := tapLineRe.FindStringSubmatch(trimmed)
+		if m == nil {
+			continue
+		}
+
+		status := "failed"
+		if m[1] == "ok" {
+			status = "passed"
+		}
+
+		desc := m[2]
+		if dm := tapDirective.FindStringSubmatch(desc); dm != nil {
+			desc = strings.TrimSpace(dm[1])
+			if strings.EqualFold(dm[2], "SKIP") {
+				status = "skipped"
+			}
+		}
+
+		message, consumed := parseTAPDiagnostic(lines[i+1:])
+		i += consumed
+
+		results = append(results, TestResult{
+			Name:     desc,
+			Status:   status,
+			Category: categoryMap.Categorize(desc),
+			Message:  message,
+		})
+	}
+	return results, nil
+}
+
+// parseTAPDiagnostic reads an optional "  ---" / "  ..." YAML diagnostic
+// block from the start of lines, returning its "message" field (if any) and
+// the number of lines consumed.
+func parseTAPDiagnostic(lines []string) (message string, consumed int) {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return "", 0
+	}
+
+	var block []string
+	i := 1
+	for i < len(lines) && strings.TrimSpace(lines[i]) != "..." {
+		block = append(block, lines[i])
+		i++
+	}
+	if i < len(lines) {
+		i++ // consume the closing "..."
+	}
+
+	var diag struct {
+		Message string `json:"message"`
+	}
+	if err := yaml.Unmarshal([]byte(strings.Join(block, "\n")), &diag); err == nil {
+		message = diag.Message
+	}
+	return message, i
+}
@@ -0,0 +1,42 @@
+package ingest
+
+import "testing"
+
+const tapFixture = `TAP version 13
+1..4
+ok 1 - rejects negative amount
+not ok 2 - rejects sql injection in search
+  ---
+  message: 'input was not sanitized'
+  ...
+ok 3 - optional integration check # SKIP not configured
+not ok 4 - known flaky test # TODO fix flakiness
+`
+
+func TestParseTAP(t *testing.T) {
+	categoryMap, err := ParseCategoryMap([]string{"security:(?i)injection"})
+	if err != nil {
+		t.Fatalf("ParseCategoryMap: %v", err)
+	}
+
+	results, err := ParseTAP([]byte(tapFixture), categoryMap)
+	if err != nil {
+		t.Fatalf("ParseTAP: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4: %+v", len(results), results)
+	}
+
+	if results[0].Status != "passed" {
+		t.Errorf("result[0].Status = %q, want passed", results[0].Status)
+	}
+	if results[1].Status != "failed" || results[1].Category != "security" || results[1].Message != "input was not sanitized" {
+		t.Errorf("result[1] = %+v, want failed/security with diagnostic message", results[1])
+	}
+	if results[2].Status != "skipped" || results[2].Name != "optional integration check" {
+		t.Errorf("result[2] = %+v, want skipped with directive stripped", results[2])
+	}
+	if results[3].Status != "failed" || results[3].Name != "known flaky test" {
+		t.Errorf("result[3] = %+v, want failed TODO test with directive stripped", results[3])
+	}
+}
@@ -1,5 +1,5 @@
 // Gap Score Reference Validator (Go)
-// Computes Gap Score from test result files (JSON format).
+// Computes Gap Score from test result files (JSON, YAML, JUnit XML or TAP).
 // Conforms to Gap Score Spec v1.0.0.
 //
 // Usage:
@@ -7,6 +7,11 @@
 //	go run gap-score.go --sealed sealed-results.json --open open-results.json
 //	go run gap-score.go --sealed sealed-results.json --threshold 15
 //	go run gap-score.go --sealed sealed-results.json --format summary
+//	go run gap-score.go --sealed sealed-results.yaml --input-format yaml
+//	go run gap-score.go --sealed junit.xml --input-format junit --category-map 'security:(?i)sec'
+//	go run gap-score.go --sealed sealed-results.json --strict
+//	go run gap-score.go --sealed sealed-results.json --history .gap-score-history --run-label ci-1234
+//	go run gap-score.go --trend --history .gap-score-history --format summary
 package main
 
 import (
@@ -15,7 +20,13 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/DUBSOpenHub/gap-score-spec/internal/ingest"
 )
 
 const specVersion = "1.0.0"
@@ -41,6 +52,7 @@ type TestResult struct {
 	Name     string `json:"name"`
 	Status   string `json:"status"`
 	Category string `json:"category,omitempty"`
+	Severity string `json:"severity,omitempty"`
 	Expected string `json:"expected,omitempty"`
 	Actual   string `json:"actual,omitempty"`
 	Message  string `json:"message,omitempty"`
@@ -50,9 +62,12 @@ type inputFile struct {
 	Tests []TestResult `json:"tests"`
 }
 
-// Failure is a single failure entry in the output report.
+// Failure is a single failure entry in the output report: a sealed test
+// whose status is "failed" or "error". Errored tests are listed even when
+// the policy's count_errored leaves them out of the Gap Score.
 type Failure struct {
 	TestName string `json:"test_name"`
+	Status   string `json:"status"`
 	Category string `json:"category"`
 	Expected string `json:"expected"`
 	Actual   string `json:"actual"`
@@ -65,9 +80,31 @@ type reportSummary struct {
 }
 
 type testStats struct {
-	Total  int `json:"total"`
-	Passed int `json:"passed"`
-	Failed int `json:"failed"`
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+	Errored int `json:"errored"`
+}
+
+// countByStatus computes a testStats from tests' status field. Any status
+// other than "failed", "skipped", or "error" (including unrecognized ones,
+// which --strict would otherwise have rejected) is counted as passed.
+func countByStatus(tests []TestResult) testStats {
+	stats := testStats{Total: len(tests)}
+	for _, t := range tests {
+		switch t.Status {
+		case "failed":
+			stats.Failed++
+		case "skipped":
+			stats.Skipped++
+		case "error":
+			stats.Errored++
+		default:
+			stats.Passed++
+		}
+	}
+	return stats
 }
 
 // CategoryComparison holds per-category sealed vs open counts.
@@ -85,15 +122,7 @@ type Report struct {
 	OpenTests          *testStats                    `json:"open_tests,omitempty"`
 	Failures           []Failure                     `json:"failures"`
 	CoverageComparison map[string]CategoryComparison `json:"coverage_comparison,omitempty"`
-}
-
-func classifyGap(score float64) (string, string) {
-	for _, l := range levels {
-		if score <= l.threshold {
-			return l.name, l.indicator
-		}
-	}
-	return "critical", "🔴"
+	ScoringPolicy      effectivePolicy               `json:"scoring_policy"`
 }
 
 // round1 rounds f to one decimal place, matching Python's round(f, 1).
@@ -101,37 +130,129 @@ func round1(f float64) float64 {
 	return math.Round(f*10) / 10
 }
 
-func loadResults(path string) ([]TestResult, error) {
+// resolveInputFormat decides how path should be read: "json", "yaml",
+// "junit", or "tap". An explicit override (from --input-format) always wins;
+// otherwise the file extension is consulted, and as a last resort the
+// content is sniffed: a leading '{' or '[' (ignoring whitespace) means JSON,
+// anything else is treated as YAML.
+func resolveInputFormat(path string, data []byte, override string) string {
+	if override != "" && override != "auto" {
+		return override
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	case ".xml":
+		return "junit"
+	case ".tap":
+		return "tap"
+	}
+	for _, b := range data {
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '{', '[':
+			return "json"
+		}
+		break
+	}
+	return "yaml"
+}
+
+func loadResults(path string, inputFormat string, categoryMap ingest.CategoryMap) ([]TestResult, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	var in inputFile
-	if err := json.Unmarshal(data, &in); err != nil {
-		return nil, err
+
+	format := resolveInputFormat(path, data, inputFormat)
+	switch format {
+	case "json":
+		var in inputFile
+		if err := json.Unmarshal(data, &in); err != nil {
+			return nil, err
+		}
+		if in.Tests == nil {
+			return []TestResult{}, nil
+		}
+		return in.Tests, nil
+	case "yaml":
+		// Normalize to JSON first so the rest of the pipeline only ever
+		// deals with one schema representation.
+		jsonData, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("converting YAML to JSON: %w", err)
+		}
+		var in inputFile
+		if err := json.Unmarshal(jsonData, &in); err != nil {
+			return nil, err
+		}
+		if in.Tests == nil {
+			return []TestResult{}, nil
+		}
+		return in.Tests, nil
+	case "junit":
+		ingested, err := ingest.ParseJUnit(data, categoryMap)
+		if err != nil {
+			return nil, err
+		}
+		return fromIngestResults(ingested), nil
+	case "tap":
+		ingested, err := ingest.ParseTAP(data, categoryMap)
+		if err != nil {
+			return nil, err
+		}
+		return fromIngestResults(ingested), nil
+	default:
+		return nil, fmt.Errorf("unsupported --input-format %q", inputFormat)
 	}
-	if in.Tests == nil {
-		return []TestResult{}, nil
+}
+
+// fromIngestResults adapts the ingest package's generic TestResult shape
+// onto this package's schema so buildReport never has to know where a
+// result originally came from.
+func fromIngestResults(in []ingest.TestResult) []TestResult {
+	out := make([]TestResult, 0, len(in))
+	for _, t := range in {
+		out = append(out, TestResult{
+			Name:     t.Name,
+			Status:   t.Status,
+			Category: t.Category,
+			Message:  t.Message,
+		})
 	}
-	return in.Tests, nil
+	return out
 }
 
-func buildReport(sealed []TestResult, open []TestResult, hasOpen bool) Report {
-	total := len(sealed)
+func buildReport(sealed []TestResult, open []TestResult, hasOpen bool, ep effectivePolicy) Report {
+	sealedStats := countByStatus(sealed)
 
 	var rawFailures []TestResult
 	for _, t := range sealed {
-		if t.Status == "failed" {
+		if isFailureWeight(t) {
 			rawFailures = append(rawFailures, t)
 		}
 	}
-	passed := total - len(rawFailures)
+
+	var totalWeight, failedWeight float64
+	for _, t := range sealed {
+		if !ep.countsTowardScore(t) {
+			continue
+		}
+		w := ep.weightFor(t)
+		totalWeight += w
+		if isFailureWeight(t) {
+			failedWeight += w
+		}
+	}
 
 	var score float64
-	if total > 0 {
-		score = round1(float64(len(rawFailures)) / float64(total) * 100)
+	if totalWeight > 0 {
+		score = round1(failedWeight / totalWeight * 100)
 	}
-	level, _ := classifyGap(score)
+	level, _ := ep.classify(score)
 
 	failures := make([]Failure, 0, len(rawFailures))
 	for _, f := range rawFailures {
@@ -141,6 +262,7 @@ func buildReport(sealed []TestResult, open []TestResult, hasOpen bool) Report {
 		}
 		failures = append(failures, Failure{
 			TestName: f.Name,
+			Status:   f.Status,
 			Category: cat,
 			Expected: f.Expected,
 			Actual:   f.Actual,
@@ -154,27 +276,14 @@ func buildReport(sealed []TestResult, open []TestResult, hasOpen bool) Report {
 			GapScore: score,
 			Level:    level,
 		},
-		SealedTests: testStats{
-			Total:  total,
-			Passed: passed,
-			Failed: len(rawFailures),
-		},
-		Failures: failures,
+		SealedTests:   sealedStats,
+		Failures:      failures,
+		ScoringPolicy: ep,
 	}
 
 	if hasOpen {
-		oTotal := len(open)
-		oFailed := 0
-		for _, t := range open {
-			if t.Status == "failed" {
-				oFailed++
-			}
-		}
-		report.OpenTests = &testStats{
-			Total:  oTotal,
-			Passed: oTotal - oFailed,
-			Failed: oFailed,
-		}
+		openStats := countByStatus(open)
+		report.OpenTests = &openStats
 
 		comparison := make(map[string]CategoryComparison, len(categories))
 		for _, cat := range categories {
@@ -201,10 +310,33 @@ func buildReport(sealed []TestResult, open []TestResult, hasOpen bool) Report {
 	return report
 }
 
+// stringSliceFlag collects repeated occurrences of a flag (e.g. multiple
+// --category-map rules) into a slice, in the order given.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string { return strings.Join(*s, ",") }
+
+func (s *stringSliceFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
 func main() {
-	sealedPath := flag.String("sealed", "", "Path to sealed test results JSON (required)")
-	openPath := flag.String("open", "", "Path to open test results JSON (optional)")
+	sealedPath := flag.String("sealed", "", "Path to sealed test results file (required)")
+	openPath := flag.String("open", "", "Path to open test results file (optional)")
 	format := flag.String("format", "json", "Output format: json or summary")
+	inputFormat := flag.String("input-format", "auto", "Input format: auto, json, yaml, junit, or tap")
+	policyPath := flag.String("policy", "", "Path to a JSON/YAML scoring policy file (category/severity weights, level thresholds)")
+	explain := flag.Bool("explain", false, "Print each failure's weighted contribution to the Gap Score")
+	strict := flag.Bool("strict", false, "Reject invalid input (unknown status, unknown category, empty or duplicate names) instead of warning")
+	allowUnknownCategories := flag.Bool("allow-unknown-categories", false, "Don't flag categories outside the known set as invalid")
+	historyDir := flag.String("history", "", "Append this run's report to a history log in this directory")
+	runLabel := flag.String("run-label", "", "Optional label recorded with --history")
+	trend := flag.Bool("trend", false, "Print a trend report from --history instead of scoring --sealed")
+	trendWindow := flag.Int("trend-window", 10, "Number of most recent --history runs to average for --trend")
+
+	var categoryMapSpecs stringSliceFlag
+	flag.Var(&categoryMapSpecs, "category-map", "Regex rule 'category:pattern' for inferring category from JUnit/TAP test names (repeatable)")
 
 	var thresholdVal float64
 	var hasThreshold bool
@@ -220,34 +352,58 @@ func main() {
 
 	flag.Parse()
 
+	if *trend {
+		runTrend(*historyDir, *trendWindow, *format)
+		return
+	}
+
 	if *sealedPath == "" {
 		fmt.Fprintln(os.Stderr, "Error: --sealed is required")
 		flag.Usage()
 		os.Exit(2)
 	}
 
-	sealed, err := loadResults(*sealedPath)
+	categoryMap, err := ingest.ParseCategoryMap(categoryMapSpecs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	policy, err := loadScoringPolicy(*policyPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading scoring policy: %v\n", err)
+		os.Exit(2)
+	}
+	ep := resolvePolicy(policy)
+
+	sealed, err := loadResults(*sealedPath, *inputFormat, categoryMap)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading sealed results: %v\n", err)
 		os.Exit(2)
 	}
+	if ok := validateOrWarn("sealed", sealed, *strict, *allowUnknownCategories); !ok {
+		os.Exit(2)
+	}
 
 	hasOpen := *openPath != ""
 	var open []TestResult
 	if hasOpen {
-		open, err = loadResults(*openPath)
+		open, err = loadResults(*openPath, *inputFormat, categoryMap)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading open results: %v\n", err)
 			os.Exit(2)
 		}
+		if ok := validateOrWarn("open", open, *strict, *allowUnknownCategories); !ok {
+			os.Exit(2)
+		}
 	}
 
-	report := buildReport(sealed, open, hasOpen)
+	report := buildReport(sealed, open, hasOpen, ep)
 
 	if *format == "summary" {
 		score := report.Report.GapScore
 		level := report.Report.Level
-		_, indicator := classifyGap(score)
+		_, indicator := ep.classify(score)
 		fmt.Printf("Gap Score: %.1f%% %s (%s)\n", score, indicator, level)
 		fmt.Printf("Sealed: %d/%d passed\n", report.SealedTests.Passed, report.SealedTests.Total)
 		if report.OpenTests != nil {
@@ -256,7 +412,11 @@ func main() {
 		if len(report.Failures) > 0 {
 			fmt.Printf("\nFailures (%d):\n", len(report.Failures))
 			for _, f := range report.Failures {
-				fmt.Printf("  ❌ %s: %s\n", f.TestName, f.Message)
+				icon := "❌"
+				if f.Status == "error" {
+					icon = "⚠️"
+				}
+				fmt.Printf("  %s %s: %s\n", icon, f.TestName, f.Message)
 			}
 		}
 	} else {
@@ -268,7 +428,56 @@ func main() {
 		fmt.Println(string(out))
 	}
 
+	if *explain {
+		if lines := explainFailures(sealed, ep); len(lines) > 0 {
+			fmt.Println("\nExplain:")
+			for _, line := range lines {
+				fmt.Println(line)
+			}
+		}
+	}
+
+	if *historyDir != "" {
+		if err := appendHistory(*historyDir, *runLabel, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error appending to history: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
 	if hasThreshold && report.Report.GapScore > thresholdVal {
 		os.Exit(1)
 	}
 }
+
+// runTrend reads the --history log in dir and prints a trend report instead
+// of scoring a --sealed run.
+func runTrend(dir string, window int, format string) {
+	if dir == "" {
+		fmt.Fprintln(os.Stderr, "Error: --trend requires --history <dir>")
+		os.Exit(2)
+	}
+
+	entries, err := readHistory(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+		os.Exit(2)
+	}
+
+	trendReport, err := buildTrendReport(entries, window)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	if format == "summary" {
+		printTrendSummary(trendReport)
+		return
+	}
+
+	out, err := json.MarshalIndent(trendReport, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error marshaling trend report: %v\n", err)
+		os.Exit(2)
+	}
+	fmt.Println(string(out))
+}
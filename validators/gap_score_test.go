@@ -0,0 +1,100 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DUBSOpenHub/gap-score-spec/internal/ingest"
+)
+
+// TestLoadResultsYAMLMatchesJSON verifies that the YAML and JSON fixtures
+// describe the same test suite and that buildReport produces an identical
+// Report regardless of which one was loaded.
+func TestLoadResultsYAMLMatchesJSON(t *testing.T) {
+	jsonTests, err := loadResults("testdata/sample.json", "auto", nil)
+	if err != nil {
+		t.Fatalf("loading JSON fixture: %v", err)
+	}
+	yamlTests, err := loadResults("testdata/sample.yaml", "auto", nil)
+	if err != nil {
+		t.Fatalf("loading YAML fixture: %v", err)
+	}
+
+	if !reflect.DeepEqual(jsonTests, yamlTests) {
+		t.Fatalf("parsed tests differ:\nJSON: %+v\nYAML: %+v", jsonTests, yamlTests)
+	}
+
+	ep := resolvePolicy(nil)
+	jsonReport := buildReport(jsonTests, nil, false, ep)
+	yamlReport := buildReport(yamlTests, nil, false, ep)
+	if !reflect.DeepEqual(jsonReport, yamlReport) {
+		t.Fatalf("reports differ:\nJSON: %+v\nYAML: %+v", jsonReport, yamlReport)
+	}
+}
+
+// TestLoadResultsJUnitAndTAP verifies that the JUnit and TAP fixtures, which
+// describe the same suite as testdata/sample.json, ingest to the same
+// failure/pass counts once run through the category map.
+func TestLoadResultsJUnitAndTAP(t *testing.T) {
+	categoryMap, err := ingest.ParseCategoryMap([]string{"security:(?i)(security|injection)", "edge_case:(?i)edgecase", "happy_path:(?i)happypath"})
+	if err != nil {
+		t.Fatalf("parsing category map: %v", err)
+	}
+
+	junitTests, err := loadResults("testdata/sample.junit.xml", "auto", categoryMap)
+	if err != nil {
+		t.Fatalf("loading JUnit fixture: %v", err)
+	}
+	tapTests, err := loadResults("testdata/sample.tap", "auto", categoryMap)
+	if err != nil {
+		t.Fatalf("loading TAP fixture: %v", err)
+	}
+
+	jsonTests, err := loadResults("testdata/sample.json", "auto", nil)
+	if err != nil {
+		t.Fatalf("loading JSON fixture: %v", err)
+	}
+
+	ep := resolvePolicy(nil)
+	jsonReport := buildReport(jsonTests, nil, false, ep)
+	junitReport := buildReport(junitTests, nil, false, ep)
+	tapReport := buildReport(tapTests, nil, false, ep)
+
+	if junitReport.Report.GapScore != jsonReport.Report.GapScore {
+		t.Errorf("JUnit gap score = %v, want %v", junitReport.Report.GapScore, jsonReport.Report.GapScore)
+	}
+	if tapReport.Report.GapScore != jsonReport.Report.GapScore {
+		t.Errorf("TAP gap score = %v, want %v", tapReport.Report.GapScore, jsonReport.Report.GapScore)
+	}
+	if len(junitReport.Failures) != 1 || junitReport.Failures[0].Category != "security" {
+		t.Errorf("JUnit failures = %+v, want one security failure", junitReport.Failures)
+	}
+	if len(tapReport.Failures) != 1 || tapReport.Failures[0].Category != "security" {
+		t.Errorf("TAP failures = %+v, want one security failure", tapReport.Failures)
+	}
+}
+
+func TestResolveInputFormat(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		data     string
+		override string
+		want     string
+	}{
+		{"explicit override wins", "results.txt", "tests: []", "yaml", "yaml"},
+		{"yaml extension", "results.yaml", "tests: []", "auto", "yaml"},
+		{"yml extension", "results.yml", "tests: []", "auto", "yaml"},
+		{"json extension", "results.json", `{"tests":[]}`, "auto", "json"},
+		{"sniff json", "results.txt", `  {"tests":[]}`, "auto", "json"},
+		{"sniff yaml", "results.txt", "tests: []", "auto", "yaml"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := resolveInputFormat(c.path, []byte(c.data), c.override)
+			if got != c.want {
+				t.Errorf("resolveInputFormat(%q, %q, %q) = %q, want %q", c.path, c.data, c.override, got, c.want)
+			}
+		})
+	}
+}
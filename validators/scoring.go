@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const defaultWeight = 1.0
+
+// defaultSeverityWeights are the built-in weights for TestResult.Severity
+// when a policy file does not override them.
+var defaultSeverityWeights = map[string]float64{
+	"low":      1.0,
+	"medium":   2.0,
+	"high":     3.0,
+	"critical": 5.0,
+}
+
+// levelOverride is the JSON/YAML shape of a --policy "levels" entry,
+// mirroring levelDef with exported fields so it can be unmarshaled.
+type levelOverride struct {
+	Threshold float64 `json:"threshold"`
+	Name      string  `json:"name"`
+	Indicator string  `json:"indicator"`
+}
+
+// ScoringPolicy is the on-disk shape of a --policy file. Any field left
+// unset keeps its built-in default, so under the empty policy every test
+// without a severity weighs 1.0.
+type ScoringPolicy struct {
+	CategoryWeights map[string]float64 `json:"category_weights,omitempty"`
+	SeverityWeights map[string]float64 `json:"severity_weights,omitempty"`
+	DefaultWeight   *float64           `json:"default_weight,omitempty"`
+	Levels          []levelOverride    `json:"levels,omitempty"`
+	// CountSkipped and CountErrored decide whether skipped/errored tests
+	// contribute to the Gap Score denominator at all. Skipped tests are
+	// excluded by default (a skip isn't a verdict); errored tests are
+	// included, and weighted as failures, since an error is a failure to
+	// get a verdict.
+	CountSkipped *bool `json:"count_skipped,omitempty"`
+	CountErrored *bool `json:"count_errored,omitempty"`
+}
+
+// loadScoringPolicy reads a JSON or YAML policy file. An empty path is not
+// an error: it simply means "no policy", i.e. the default weights.
+func loadScoringPolicy(path string) (*ScoringPolicy, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".yaml" || ext == ".yml" {
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("converting policy YAML to JSON: %w", err)
+		}
+	}
+
+	var policy ScoringPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("parsing scoring policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// effectivePolicy is the fully-resolved policy (defaults merged with any
+// --policy overrides) used to score a run. It is embedded verbatim into the
+// Report's scoring_policy key for auditability.
+type effectivePolicy struct {
+	CategoryWeights map[string]float64 `json:"category_weights"`
+	SeverityWeights map[string]float64 `json:"severity_weights"`
+	DefaultWeight   float64            `json:"default_weight"`
+	CountSkipped    bool               `json:"count_skipped"`
+	CountErrored    bool               `json:"count_errored"`
+	levels          []levelDef
+}
+
+// resolvePolicy merges a possibly-nil ScoringPolicy onto the built-in
+// defaults. A nil policy weights every category at 1.0, uses
+// defaultSeverityWeights for tests that set a severity, excludes skipped
+// tests, counts errored tests as failures, and keeps the built-in level
+// thresholds.
+func resolvePolicy(policy *ScoringPolicy) effectivePolicy {
+	ep := effectivePolicy{
+		DefaultWeight: defaultWeight,
+		CountSkipped:  false,
+		CountErrored:  true,
+		levels:        levels,
+	}
+	if policy != nil && policy.DefaultWeight != nil {
+		ep.DefaultWeight = *policy.DefaultWeight
+	}
+	if policy != nil && policy.CountSkipped != nil {
+		ep.CountSkipped = *policy.CountSkipped
+	}
+	if policy != nil && policy.CountErrored != nil {
+		ep.CountErrored = *policy.CountErrored
+	}
+
+	ep.CategoryWeights = make(map[string]float64, len(categories))
+	for _, cat := range categories {
+		ep.CategoryWeights[cat] = ep.DefaultWeight
+	}
+	ep.SeverityWeights = make(map[string]float64, len(defaultSeverityWeights))
+	for severity, weight := range defaultSeverityWeights {
+		ep.SeverityWeights[severity] = weight
+	}
+
+	if policy == nil {
+		return ep
+	}
+	for cat, weight := range policy.CategoryWeights {
+		ep.CategoryWeights[cat] = weight
+	}
+	for severity, weight := range policy.SeverityWeights {
+		ep.SeverityWeights[severity] = weight
+	}
+	if len(policy.Levels) > 0 {
+		custom := make([]levelDef, 0, len(policy.Levels))
+		for _, l := range policy.Levels {
+			custom = append(custom, levelDef{threshold: l.Threshold, name: l.Name, indicator: l.Indicator})
+		}
+		ep.levels = custom
+	}
+	return ep
+}
+
+// weightFor returns the weight a test contributes to the Gap Score: its
+// severity weight if Severity is set and known, else its category weight,
+// else the policy's default weight.
+func (ep effectivePolicy) weightFor(t TestResult) float64 {
+	if t.Severity != "" {
+		if w, ok := ep.SeverityWeights[t.Severity]; ok {
+			return w
+		}
+	}
+	if w, ok := ep.CategoryWeights[t.Category]; ok {
+		return w
+	}
+	return ep.DefaultWeight
+}
+
+// countsTowardScore reports whether t should contribute to the Gap Score
+// denominator at all, per ep.CountSkipped/ep.CountErrored.
+func (ep effectivePolicy) countsTowardScore(t TestResult) bool {
+	switch t.Status {
+	case "skipped":
+		return ep.CountSkipped
+	case "error":
+		return ep.CountErrored
+	default:
+		return true
+	}
+}
+
+// isFailureWeight reports whether t's weight should count against the score
+// (as opposed to merely being part of the denominator).
+func isFailureWeight(t TestResult) bool {
+	return t.Status == "failed" || t.Status == "error"
+}
+
+// classify maps a weighted score onto this policy's level thresholds.
+func (ep effectivePolicy) classify(score float64) (string, string) {
+	for _, l := range ep.levels {
+		if score <= l.threshold {
+			return l.name, l.indicator
+		}
+	}
+	if len(ep.levels) == 0 {
+		return "critical", "🔴"
+	}
+	last := ep.levels[len(ep.levels)-1]
+	return last.name, last.indicator
+}
+
+// explainFailures formats, per failed sealed test, how much it contributed
+// to the overall Gap Score under ep — printed by --explain.
+func explainFailures(sealed []TestResult, ep effectivePolicy) []string {
+	var totalWeight float64
+	for _, t := range sealed {
+		if ep.countsTowardScore(t) {
+			totalWeight += ep.weightFor(t)
+		}
+	}
+
+	var lines []string
+	for _, t := range sealed {
+		if !isFailureWeight(t) || !ep.countsTowardScore(t) {
+			continue
+		}
+		weight := ep.weightFor(t)
+		var contribution float64
+		if totalWeight > 0 {
+			contribution = weight / totalWeight * 100
+		}
+		lines = append(lines, fmt.Sprintf("  %s: weight %.2f -> %.1f%% of score", t.Name, weight, contribution))
+	}
+	return lines
+}
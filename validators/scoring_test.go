@@ -0,0 +1,139 @@
+package main
+
+import "testing"
+
+func TestBuildReportDefaultPolicyMatchesUnweightedScore(t *testing.T) {
+	sealed := []TestResult{
+		{Name: "a", Status: "passed", Category: "happy_path"},
+		{Name: "b", Status: "failed", Category: "security"},
+		{Name: "c", Status: "passed", Category: "edge_case"},
+		{Name: "d", Status: "failed", Category: "happy_path"},
+	}
+
+	report := buildReport(sealed, nil, false, resolvePolicy(nil))
+	if report.Report.GapScore != 50.0 {
+		t.Errorf("GapScore = %v, want 50.0 (2/4 failed, unweighted)", report.Report.GapScore)
+	}
+}
+
+func TestBuildReportWeightedPolicy(t *testing.T) {
+	weight := 3.0
+	policy := &ScoringPolicy{
+		CategoryWeights: map[string]float64{"security": weight, "happy_path": 1.0},
+	}
+	ep := resolvePolicy(policy)
+
+	sealed := []TestResult{
+		{Name: "happy", Status: "passed", Category: "happy_path"},
+		{Name: "sec", Status: "failed", Category: "security"},
+		{Name: "other-happy", Status: "passed", Category: "happy_path"},
+	}
+
+	report := buildReport(sealed, nil, false, ep)
+	// total weight = 1 + 3 + 1 = 5, failed weight = 3 -> 60%
+	if report.Report.GapScore != 60.0 {
+		t.Errorf("GapScore = %v, want 60.0", report.Report.GapScore)
+	}
+	if report.ScoringPolicy.CategoryWeights["security"] != weight {
+		t.Errorf("scoring_policy did not retain the security weight override")
+	}
+}
+
+func TestBuildReportSeverityOverridesCategory(t *testing.T) {
+	policy := &ScoringPolicy{
+		CategoryWeights: map[string]float64{"security": 2.0},
+		SeverityWeights: map[string]float64{"critical": 10.0},
+	}
+	ep := resolvePolicy(policy)
+
+	sealed := []TestResult{
+		{Name: "a", Status: "passed", Category: "security"},
+		{Name: "b", Status: "failed", Category: "security", Severity: "critical"},
+	}
+
+	report := buildReport(sealed, nil, false, ep)
+	// total weight = 2 + 10 = 12, failed weight = 10 -> 83.3%
+	if report.Report.GapScore != 83.3 {
+		t.Errorf("GapScore = %v, want 83.3 (severity should win over category)", report.Report.GapScore)
+	}
+}
+
+func TestResolvePolicyCustomLevels(t *testing.T) {
+	policy := &ScoringPolicy{
+		Levels: []levelOverride{
+			{Threshold: 10, Name: "ok", Indicator: "✅"},
+			{Threshold: 100, Name: "critical", Indicator: "🔴"},
+		},
+	}
+	ep := resolvePolicy(policy)
+
+	name, _ := ep.classify(5)
+	if name != "ok" {
+		t.Errorf("classify(5) = %q, want ok", name)
+	}
+	name, _ = ep.classify(50)
+	if name != "critical" {
+		t.Errorf("classify(50) = %q, want critical", name)
+	}
+}
+
+func TestBuildReportSkippedExcludedByDefault(t *testing.T) {
+	sealed := []TestResult{
+		{Name: "a", Status: "passed"},
+		{Name: "b", Status: "failed"},
+		{Name: "c", Status: "skipped"},
+	}
+	report := buildReport(sealed, nil, false, resolvePolicy(nil))
+	// skipped is excluded from the denominator by default: 1 failed / 2 counted = 50%
+	if report.Report.GapScore != 50.0 {
+		t.Errorf("GapScore = %v, want 50.0 (skipped excluded)", report.Report.GapScore)
+	}
+	if report.SealedTests.Skipped != 1 {
+		t.Errorf("SealedTests.Skipped = %d, want 1", report.SealedTests.Skipped)
+	}
+}
+
+func TestBuildReportErroredCountsAsFailureByDefault(t *testing.T) {
+	sealed := []TestResult{
+		{Name: "a", Status: "passed"},
+		{Name: "b", Status: "error"},
+	}
+	report := buildReport(sealed, nil, false, resolvePolicy(nil))
+	if report.Report.GapScore != 50.0 {
+		t.Errorf("GapScore = %v, want 50.0 (errored counts as failure)", report.Report.GapScore)
+	}
+	if report.SealedTests.Errored != 1 {
+		t.Errorf("SealedTests.Errored = %d, want 1", report.SealedTests.Errored)
+	}
+	if len(report.Failures) != 1 || report.Failures[0].TestName != "b" || report.Failures[0].Status != "error" {
+		t.Errorf("Failures = %+v, want one entry for the errored test", report.Failures)
+	}
+}
+
+func TestBuildReportCountSkippedOverride(t *testing.T) {
+	countSkipped := true
+	ep := resolvePolicy(&ScoringPolicy{CountSkipped: &countSkipped})
+	sealed := []TestResult{
+		{Name: "a", Status: "passed"},
+		{Name: "b", Status: "failed"},
+		{Name: "c", Status: "skipped"},
+	}
+	report := buildReport(sealed, nil, false, ep)
+	// all three counted: 1 failed / 3 = 33.3%
+	if report.Report.GapScore != 33.3 {
+		t.Errorf("GapScore = %v, want 33.3 (skipped included)", report.Report.GapScore)
+	}
+}
+
+func TestExplainFailures(t *testing.T) {
+	ep := resolvePolicy(&ScoringPolicy{CategoryWeights: map[string]float64{"security": 3.0}})
+	sealed := []TestResult{
+		{Name: "pass", Status: "passed", Category: "happy_path"},
+		{Name: "fail-sec", Status: "failed", Category: "security"},
+	}
+
+	lines := explainFailures(sealed, ep)
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1: %v", len(lines), lines)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const historyFileName = "history.ndjson"
+
+// historyEntry is one line of the --history log: a timestamped, optionally
+// labeled snapshot of a Report.
+type historyEntry struct {
+	Timestamp string `json:"timestamp"`
+	RunLabel  string `json:"run_label,omitempty"`
+	Report    Report `json:"report"`
+}
+
+// appendHistory appends report as a new line to <dir>/history.ndjson,
+// creating dir and the file as needed.
+func appendHistory(dir string, runLabel string, report Report) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	entry := historyEntry{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		RunLabel:  runLabel,
+		Report:    report,
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, historyFileName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// readHistory loads every entry previously appended to <dir>/history.ndjson,
+// oldest first.
+func readHistory(dir string) ([]historyEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, historyFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []historyEntry
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var entry historyEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("parsing history entry on line %d: %w", i+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// TrendReport summarizes a window of history entries: how the Gap Score is
+// moving, which tests fail most often, and what changed between the two
+// most recent runs.
+type TrendReport struct {
+	RunsConsidered   int            `json:"runs_considered"`
+	RollingGapScore  float64        `json:"rolling_gap_score"`
+	FailureFrequency map[string]int `json:"failure_frequency"`
+	Regressions      []string       `json:"regressions"`
+	Fixes            []string       `json:"fixes"`
+}
+
+// buildTrendReport summarizes the most recent window entries (or all of
+// them, if window <= 0 or larger than the history). Regressions and fixes
+// are computed only between the two most recent entries, since "did this
+// get worse or better since last time" only makes sense run-over-run.
+func buildTrendReport(entries []historyEntry, window int) (TrendReport, error) {
+	if len(entries) == 0 {
+		return TrendReport{}, fmt.Errorf("no history entries found")
+	}
+	if window <= 0 || window > len(entries) {
+		window = len(entries)
+	}
+	recent := entries[len(entries)-window:]
+
+	var scoreSum float64
+	failureFrequency := make(map[string]int)
+	for _, e := range recent {
+		scoreSum += e.Report.Report.GapScore
+		for _, f := range e.Report.Failures {
+			failureFrequency[f.TestName]++
+		}
+	}
+
+	var regressions, fixes []string
+	if len(entries) >= 2 {
+		prevFailed := failedNames(entries[len(entries)-2].Report)
+		latestFailed := failedNames(entries[len(entries)-1].Report)
+		for name := range latestFailed {
+			if !prevFailed[name] {
+				regressions = append(regressions, name)
+			}
+		}
+		for name := range prevFailed {
+			if !latestFailed[name] {
+				fixes = append(fixes, name)
+			}
+		}
+		sort.Strings(regressions)
+		sort.Strings(fixes)
+	}
+
+	return TrendReport{
+		RunsConsidered:   len(recent),
+		RollingGapScore:  round1(scoreSum / float64(len(recent))),
+		FailureFrequency: failureFrequency,
+		Regressions:      regressions,
+		Fixes:            fixes,
+	}, nil
+}
+
+func failedNames(report Report) map[string]bool {
+	names := make(map[string]bool, len(report.Failures))
+	for _, f := range report.Failures {
+		names[f.TestName] = true
+	}
+	return names
+}
+
+func printTrendSummary(t TrendReport) {
+	fmt.Printf("Rolling Gap Score (last %d runs): %.1f%%\n", t.RunsConsidered, t.RollingGapScore)
+
+	if len(t.FailureFrequency) > 0 {
+		type freq struct {
+			name  string
+			count int
+		}
+		freqs := make([]freq, 0, len(t.FailureFrequency))
+		for name, count := range t.FailureFrequency {
+			freqs = append(freqs, freq{name, count})
+		}
+		sort.Slice(freqs, func(i, j int) bool {
+			if freqs[i].count != freqs[j].count {
+				return freqs[i].count > freqs[j].count
+			}
+			return freqs[i].name < freqs[j].name
+		})
+		fmt.Println("\nMost frequent failures:")
+		for _, f := range freqs {
+			fmt.Printf("  %s: failed in %d/%d runs\n", f.name, f.count, t.RunsConsidered)
+		}
+	}
+
+	if len(t.Regressions) > 0 {
+		fmt.Println("\nRegressions (passed last run, failing now):")
+		for _, name := range t.Regressions {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+
+	if len(t.Fixes) > 0 {
+		fmt.Println("\nFixes (failed last run, passing now):")
+		for _, name := range t.Fixes {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+}
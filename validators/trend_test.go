@@ -0,0 +1,107 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func sampleReport(gapScore float64, failed ...string) Report {
+	failures := make([]Failure, 0, len(failed))
+	for _, name := range failed {
+		failures = append(failures, Failure{TestName: name})
+	}
+	return Report{
+		Report:   reportSummary{GapScore: gapScore},
+		Failures: failures,
+	}
+}
+
+func TestAppendAndReadHistory(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := appendHistory(dir, "run-1", sampleReport(10, "a")); err != nil {
+		t.Fatalf("appendHistory: %v", err)
+	}
+	if err := appendHistory(dir, "run-2", sampleReport(20, "b")); err != nil {
+		t.Fatalf("appendHistory: %v", err)
+	}
+
+	entries, err := readHistory(dir)
+	if err != nil {
+		t.Fatalf("readHistory: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].RunLabel != "run-1" || entries[1].RunLabel != "run-2" {
+		t.Errorf("entries out of order: %+v", entries)
+	}
+	if entries[0].Timestamp == "" {
+		t.Error("expected a non-empty timestamp")
+	}
+
+	historyPath := filepath.Join(dir, historyFileName)
+	if _, err := readHistory(filepath.Dir(historyPath)); err != nil {
+		t.Errorf("unexpected error re-reading history: %v", err)
+	}
+}
+
+func TestBuildTrendReportRollingScoreAndFrequency(t *testing.T) {
+	entries := []historyEntry{
+		{Report: sampleReport(10, "a")},
+		{Report: sampleReport(20, "a", "b")},
+		{Report: sampleReport(30, "a")},
+	}
+
+	trend, err := buildTrendReport(entries, 0)
+	if err != nil {
+		t.Fatalf("buildTrendReport: %v", err)
+	}
+	if trend.RunsConsidered != 3 {
+		t.Errorf("RunsConsidered = %d, want 3", trend.RunsConsidered)
+	}
+	if trend.RollingGapScore != 20.0 {
+		t.Errorf("RollingGapScore = %v, want 20.0", trend.RollingGapScore)
+	}
+	if trend.FailureFrequency["a"] != 3 || trend.FailureFrequency["b"] != 1 {
+		t.Errorf("FailureFrequency = %+v, want a:3 b:1", trend.FailureFrequency)
+	}
+}
+
+func TestBuildTrendReportRegressionsAndFixes(t *testing.T) {
+	entries := []historyEntry{
+		{Report: sampleReport(10, "a")},
+		{Report: sampleReport(10, "b")},
+	}
+
+	trend, err := buildTrendReport(entries, 0)
+	if err != nil {
+		t.Fatalf("buildTrendReport: %v", err)
+	}
+	if len(trend.Regressions) != 1 || trend.Regressions[0] != "b" {
+		t.Errorf("Regressions = %v, want [b]", trend.Regressions)
+	}
+	if len(trend.Fixes) != 1 || trend.Fixes[0] != "a" {
+		t.Errorf("Fixes = %v, want [a]", trend.Fixes)
+	}
+}
+
+func TestBuildTrendReportWindow(t *testing.T) {
+	entries := []historyEntry{
+		{Report: sampleReport(0)},
+		{Report: sampleReport(100)},
+	}
+	trend, err := buildTrendReport(entries, 1)
+	if err != nil {
+		t.Fatalf("buildTrendReport: %v", err)
+	}
+	if trend.RunsConsidered != 1 || trend.RollingGapScore != 100.0 {
+		t.Errorf("windowed trend = %+v, want considering only the latest run at 100.0", trend)
+	}
+}
+
+func TestBuildTrendReportEmptyHistory(t *testing.T) {
+	if _, err := buildTrendReport(nil, 0); err == nil {
+		t.Error("expected an error for empty history")
+	}
+}
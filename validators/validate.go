@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+var validStatuses = map[string]bool{
+	"passed":  true,
+	"failed":  true,
+	"skipped": true,
+	"error":   true,
+}
+
+func isKnownCategory(category string) bool {
+	for _, known := range categories {
+		if category == known {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTestResults checks tests against the input schema: status must be
+// one of the known values, category (when present) must be a known one
+// unless allowUnknownCategories is set, names must be non-empty, and names
+// must be unique within the file. It returns one message per violation,
+// each naming the offending array index, in the order the violations were
+// found.
+func validateTestResults(tests []TestResult, allowUnknownCategories bool) []string {
+	var errs []string
+	var namesInOrder []string
+	indicesByName := make(map[string][]int)
+
+	for i, t := range tests {
+		if strings.TrimSpace(t.Name) == "" {
+			errs = append(errs, fmt.Sprintf("tests[%d]: name must not be empty", i))
+		} else {
+			if _, seen := indicesByName[t.Name]; !seen {
+				namesInOrder = append(namesInOrder, t.Name)
+			}
+			indicesByName[t.Name] = append(indicesByName[t.Name], i)
+		}
+
+		if !validStatuses[t.Status] {
+			errs = append(errs, fmt.Sprintf("tests[%d] %q: unknown status %q (must be one of passed, failed, skipped, error)", i, t.Name, t.Status))
+		}
+
+		if t.Category != "" && !allowUnknownCategories && !isKnownCategory(t.Category) {
+			errs = append(errs, fmt.Sprintf("tests[%d] %q: unknown category %q", i, t.Name, t.Category))
+		}
+	}
+
+	for _, name := range namesInOrder {
+		indices := indicesByName[name]
+		if len(indices) > 1 {
+			errs = append(errs, fmt.Sprintf("duplicate test name %q at indices %v", name, indices))
+		}
+	}
+
+	return errs
+}
+
+// validateOrWarn validates tests loaded from the file labeled by label. In
+// strict mode any violation is fatal; otherwise violations are printed to
+// stderr as warnings and the run continues. It returns false only when
+// strict mode should abort the run.
+func validateOrWarn(label string, tests []TestResult, strict bool, allowUnknownCategories bool) bool {
+	errs := validateTestResults(tests, allowUnknownCategories)
+	if len(errs) == 0 {
+		return true
+	}
+
+	prefix := "Warning"
+	if strict {
+		prefix = "Error"
+	}
+	fmt.Fprintf(os.Stderr, "%s: %s test results failed schema validation:\n", prefix, label)
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "  %s\n", e)
+	}
+
+	return !strict
+}
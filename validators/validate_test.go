@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestValidateTestResults(t *testing.T) {
+	tests := []TestResult{
+		{Name: "a", Status: "passed", Category: "happy_path"},
+		{Name: "", Status: "failed"},
+		{Name: "b", Status: "bogus"},
+		{Name: "c", Status: "passed", Category: "not_a_real_category"},
+		{Name: "a", Status: "passed"},
+	}
+
+	errs := validateTestResults(tests, false)
+	if len(errs) != 4 {
+		t.Fatalf("got %d errors, want 4: %v", len(errs), errs)
+	}
+}
+
+func TestValidateTestResultsAllowUnknownCategories(t *testing.T) {
+	tests := []TestResult{
+		{Name: "a", Status: "passed", Category: "custom_category"},
+	}
+	if errs := validateTestResults(tests, false); len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1 (unknown category): %v", len(errs), errs)
+	}
+	if errs := validateTestResults(tests, true); len(errs) != 0 {
+		t.Fatalf("got %d errors, want 0 with --allow-unknown-categories: %v", len(errs), errs)
+	}
+}
+
+func TestValidateOrWarnNonStrictContinues(t *testing.T) {
+	tests := []TestResult{{Name: "a", Status: "bogus"}}
+	if ok := validateOrWarn("sealed", tests, false, false); !ok {
+		t.Error("non-strict mode should continue despite violations")
+	}
+}
+
+func TestValidateOrWarnStrictRejects(t *testing.T) {
+	tests := []TestResult{{Name: "a", Status: "bogus"}}
+	if ok := validateOrWarn("sealed", tests, true, false); ok {
+		t.Error("strict mode should reject invalid results")
+	}
+}